@@ -2,12 +2,17 @@ package gcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"regexp"
 
 	hyperv1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/hypershift/cmd/cluster/core"
+	gcpinfra "github.com/openshift/hypershift/cmd/infra/gcp"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/spf13/cobra"
@@ -23,12 +28,86 @@ type RawCreateOptions struct {
 
 	// Region is the GCP region where the HostedCluster will be created
 	Region string
+
+	// InfraID uniquely identifies the infrastructure created for this
+	// cluster. It is used to prefix resource names and defaults to the
+	// cluster name if unset.
+	InfraID string
+
+	// InfraJSON is an optional path to the output of `hypershift create
+	// infra gcp`. When set, that infrastructure is reused instead of being
+	// created inline.
+	InfraJSON string
+
+	// Zones are the GCP zones to spread NodePools across. One NodePool is
+	// generated per zone. When empty, a single NodePool with no zone set is
+	// generated.
+	Zones []string
+
+	// InstanceType is the GCP machine type used for worker nodes.
+	InstanceType string
+
+	// RootVolumeSize is the size, in GiB, of each worker node's boot disk.
+	RootVolumeSize int64
+
+	// RootVolumeType is the GCP persistent disk type used for the boot disk
+	// (e.g. pd-standard, pd-ssd, pd-balanced).
+	RootVolumeType string
+
+	// RootVolumeEncryptionKey is the resource name of a Cloud KMS key used
+	// to encrypt worker boot disks (CMEK). When unset, Google-managed
+	// encryption is used.
+	RootVolumeEncryptionKey string
+
+	// NodeCount is the number of nodes to create per NodePool.
+	NodeCount int32
+
+	// AutoRepair enables the GCP instance group auto-repair health check
+	// for the NodePools' managed instance groups.
+	AutoRepair bool
+
+	// ConfidentialCompute selects the confidential-computing technology
+	// used to run worker nodes (SEV, SEV_SNP, or TDX). Empty disables it.
+	ConfidentialCompute string
+
+	// ServiceAccountEmail is the email of the GCP service account worker
+	// nodes run as. Defaults to the infra-created service account.
+	ServiceAccountEmail string
+
+	// NetworkProject is the GCP project ID that owns the shared VPC this
+	// cluster's network resources live in. Set together with Network and
+	// Subnet to use a Shared VPC (host/service project) topology instead of
+	// creating a dedicated network in Project.
+	NetworkProject string
+
+	// Network is the self-link of the shared VPC network to attach the
+	// cluster to. Required when NetworkProject is set.
+	Network string
+
+	// Subnet is the self-link of the shared VPC subnet to attach the
+	// cluster's control-plane and worker resources to. Required when
+	// NetworkProject is set.
+	Subnet string
 }
 
 // BindOptions binds the GCP-specific flags to the provided flag set
 func BindOptions(opts *RawCreateOptions, flags *pflag.FlagSet) {
 	flags.StringVar(&opts.Project, "project", opts.Project, "GCP project ID where the HostedCluster will be created")
 	flags.StringVar(&opts.Region, "region", opts.Region, "GCP region where the HostedCluster will be created")
+	flags.StringVar(&opts.InfraID, "infra-id", opts.InfraID, "Infrastructure identifier to use when naming cloud resources. Defaults to the cluster name")
+	flags.StringVar(&opts.InfraJSON, "infra-json", opts.InfraJSON, "Path to the output of a previous 'hypershift create infra gcp' invocation. When set, that infrastructure is reused instead of being created inline")
+	flags.StringArrayVar(&opts.Zones, "zone", opts.Zones, "GCP zone to create a NodePool in (may be specified multiple times). When unset, a single NodePool with no zone is created")
+	flags.StringVar(&opts.InstanceType, "instance-type", opts.InstanceType, "GCP machine type for worker nodes")
+	flags.Int64Var(&opts.RootVolumeSize, "root-volume-size", opts.RootVolumeSize, "Size, in GiB, of each worker node's boot disk")
+	flags.StringVar(&opts.RootVolumeType, "root-volume-type", opts.RootVolumeType, "GCP persistent disk type for worker boot disks (e.g. pd-standard, pd-balanced, pd-ssd)")
+	flags.StringVar(&opts.RootVolumeEncryptionKey, "root-volume-encryption-key", opts.RootVolumeEncryptionKey, "Resource name of a Cloud KMS key used to encrypt worker boot disks")
+	flags.Int32Var(&opts.NodeCount, "node-count", opts.NodeCount, "Number of nodes to create per NodePool")
+	flags.BoolVar(&opts.AutoRepair, "auto-repair", opts.AutoRepair, "Enable auto-repair on the worker managed instance groups")
+	flags.StringVar(&opts.ConfidentialCompute, "confidential-compute", opts.ConfidentialCompute, "Confidential computing technology for worker nodes (SEV, SEV_SNP, or TDX)")
+	flags.StringVar(&opts.ServiceAccountEmail, "service-account-email", opts.ServiceAccountEmail, "Email of the GCP service account worker nodes run as. Defaults to the infra-created service account")
+	flags.StringVar(&opts.NetworkProject, "network-project", opts.NetworkProject, "GCP project ID of the Shared VPC host project that owns --network and --subnet")
+	flags.StringVar(&opts.Network, "network", opts.Network, "Self-link of the Shared VPC network to attach the cluster to. Requires --network-project")
+	flags.StringVar(&opts.Subnet, "subnet", opts.Subnet, "Self-link of the Shared VPC subnet to attach the cluster to. Requires --network-project")
 }
 
 // ValidatedCreateOptions represents validated options for creating a GCP cluster
@@ -54,6 +133,28 @@ func (o *RawCreateOptions) Validate(_ context.Context, _ *core.CreateOptions) (c
 		return nil, fmt.Errorf("invalid region: %w", err)
 	}
 
+	for _, zone := range o.Zones {
+		if err := validateZone(o.Region, zone); err != nil {
+			return nil, fmt.Errorf("invalid zone %q: %w", zone, err)
+		}
+	}
+
+	if err := validateInstanceType(o.InstanceType); err != nil {
+		return nil, fmt.Errorf("invalid instance type: %w", err)
+	}
+
+	if err := validateRootVolumeSize(o.RootVolumeSize); err != nil {
+		return nil, fmt.Errorf("invalid root volume size: %w", err)
+	}
+
+	if err := validateConfidentialCompute(o.ConfidentialCompute); err != nil {
+		return nil, fmt.Errorf("invalid confidential compute option: %w", err)
+	}
+
+	if err := validateSharedVPC(o.NetworkProject, o.Network, o.Subnet); err != nil {
+		return nil, fmt.Errorf("invalid Shared VPC configuration: %w", err)
+	}
+
 	return &ValidatedCreateOptions{
 		validatedCreateOptions: &validatedCreateOptions{
 			RawCreateOptions: o,
@@ -102,12 +203,99 @@ func validateRegion(region string) error {
 	return nil
 }
 
+// validateZone validates that zone is one of region's zones, i.e. region
+// followed by a single lowercase letter suffix (e.g. us-central1-a).
+func validateZone(region, zone string) error {
+	matched, err := regexp.MatchString(`^`+regexp.QuoteMeta(region)+`-[a-z]$`, zone)
+	if err != nil {
+		return fmt.Errorf("error validating zone: %w", err)
+	}
+	if !matched {
+		return fmt.Errorf("zone must be in region %s and match the format %s-<letter> (e.g., %s-a)", region, region, region)
+	}
+	return nil
+}
+
+// validateInstanceType validates the GCP machine type format, e.g.
+// n2-standard-4, e2-medium, or a custom type like custom-4-16384.
+func validateInstanceType(instanceType string) error {
+	if instanceType == "" {
+		return fmt.Errorf("instance type cannot be empty")
+	}
+
+	matched, err := regexp.MatchString(`^[a-z][a-z0-9]*(-[a-z0-9]+)+$`, instanceType)
+	if err != nil {
+		return fmt.Errorf("error validating instance type: %w", err)
+	}
+	if !matched {
+		return fmt.Errorf("instance type must be a valid GCP machine type (e.g., n2-standard-4)")
+	}
+
+	return nil
+}
+
+// GCP persistent disks must be between 10 GiB and 64 TiB.
+const (
+	minRootVolumeSizeGiB = 10
+	maxRootVolumeSizeGiB = 65536
+)
+
+// validateRootVolumeSize validates that the requested boot disk size falls
+// within GCP persistent disk bounds.
+func validateRootVolumeSize(sizeGiB int64) error {
+	if sizeGiB == 0 {
+		return nil
+	}
+	if sizeGiB < minRootVolumeSizeGiB || sizeGiB > maxRootVolumeSizeGiB {
+		return fmt.Errorf("root volume size must be between %d and %d GiB", minRootVolumeSizeGiB, maxRootVolumeSizeGiB)
+	}
+	return nil
+}
+
+// validConfidentialComputeTypes are the confidential-computing technologies
+// GCP supports for Compute Engine instances.
+var validConfidentialComputeTypes = map[string]bool{
+	"":        true,
+	"SEV":     true,
+	"SEV_SNP": true,
+	"TDX":     true,
+}
+
+func validateConfidentialCompute(confidentialCompute string) error {
+	if !validConfidentialComputeTypes[confidentialCompute] {
+		return fmt.Errorf("confidential compute must be one of SEV, SEV_SNP, or TDX")
+	}
+	return nil
+}
+
+// validateSharedVPC validates that --network-project, --network, and
+// --subnet are either all unset (dedicated VPC, the default) or all set
+// together (Shared VPC host/service project topology).
+func validateSharedVPC(networkProject, network, subnet string) error {
+	set := networkProject != "" || network != "" || subnet != ""
+	if !set {
+		return nil
+	}
+
+	if networkProject == "" || network == "" || subnet == "" {
+		return fmt.Errorf("--network-project, --network, and --subnet must all be set together")
+	}
+
+	if err := validateProjectID(networkProject); err != nil {
+		return fmt.Errorf("invalid network project ID: %w", err)
+	}
+
+	return nil
+}
+
 // completedCreateOptions is a private wrapper that enforces a call of Complete() before cluster creation can be invoked.
 type completedCreateOptions struct {
 	*ValidatedCreateOptions
 
 	externalDNSDomain string
 	name, namespace   string
+
+	infra *gcpinfra.CreateInfraOutput
 }
 
 // CreateOptions represents the completed and validated options for creating a GCP cluster
@@ -118,19 +306,69 @@ type CreateOptions struct {
 
 // Complete completes the GCP create cluster command options
 func (o *ValidatedCreateOptions) Complete(ctx context.Context, opts *core.CreateOptions) (core.Platform, error) {
+	infraID := o.InfraID
+	if infraID == "" {
+		infraID = opts.Name
+	}
+
+	infra, err := o.resolveInfra(ctx, opts, infraID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain GCP infrastructure: %w", err)
+	}
+
 	return &CreateOptions{
 		completedCreateOptions: &completedCreateOptions{
 			ValidatedCreateOptions: o,
 			name:                   opts.Name,
 			namespace:              opts.Namespace,
 			externalDNSDomain:      opts.ExternalDNSDomain,
+			infra:                  infra,
 		},
 	}, nil
 }
 
+// resolveInfra returns the infrastructure this cluster will run on, either
+// by reading the output of a previous `hypershift create infra gcp` run or,
+// if no InfraJSON was supplied, by provisioning it inline.
+func (o *ValidatedCreateOptions) resolveInfra(ctx context.Context, opts *core.CreateOptions, infraID string) (*gcpinfra.CreateInfraOutput, error) {
+	if o.InfraJSON != "" {
+		raw, err := os.ReadFile(o.InfraJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --infra-json: %w", err)
+		}
+		infra := &gcpinfra.CreateInfraOutput{}
+		if err := json.Unmarshal(raw, infra); err != nil {
+			return nil, fmt.Errorf("failed to parse --infra-json: %w", err)
+		}
+		return infra, nil
+	}
+
+	createInfraOpts := &gcpinfra.CreateInfraOptions{
+		Project:        o.Project,
+		Region:         o.Region,
+		Zones:          o.Zones,
+		InfraID:        infraID,
+		Name:           opts.Name,
+		NetworkProject: o.NetworkProject,
+		Network:        o.Network,
+		Subnet:         o.Subnet,
+	}
+	return createInfraOpts.Run(ctx, opts.Log)
+}
+
+// defaultInstanceType is the GCP machine type used for worker nodes when
+// --instance-type is not set.
+const defaultInstanceType = "n2-standard-4"
+
 // DefaultOptions returns default options for GCP cluster creation
 func DefaultOptions() *RawCreateOptions {
-	return &RawCreateOptions{}
+	return &RawCreateOptions{
+		InstanceType:   defaultInstanceType,
+		RootVolumeSize: 128,
+		RootVolumeType: "pd-ssd",
+		NodeCount:      2,
+		AutoRepair:     true,
+	}
 }
 
 // NewCreateCommand creates a new cobra command for creating GCP clusters
@@ -167,20 +405,139 @@ func NewCreateCommand(opts *core.RawCreateOptions) *cobra.Command {
 // ApplyPlatformSpecifics applies GCP-specific configurations to the HostedCluster
 func (o *CreateOptions) ApplyPlatformSpecifics(hostedCluster *hyperv1.HostedCluster) error {
 	hostedCluster.Spec.Platform.Type = hyperv1.GCPPlatform
-	hostedCluster.Spec.Platform.GCP = &hyperv1.GCPPlatformSpec{
+	platform := &hyperv1.GCPPlatformSpec{
 		Project: o.Project,
 		Region:  o.Region,
 	}
+	if o.infra != nil {
+		platform.ServiceAccountEmail = o.infra.ServiceAccountEmail
+	}
+	switch {
+	case o.infra != nil && o.infra.NetworkHostProject != "":
+		// The resolved infra (freshly provisioned or read back via
+		// --infra-json) recorded that it used a Shared VPC; trust that
+		// over the raw flag, since --infra-json callers don't necessarily
+		// repeat --network-project at cluster-create time.
+		subnet := anySubnetSelfLink(o.infra.SubnetSelfLinks)
+		platform.Network = &hyperv1.GCPNetworkSpec{
+			HostProject:                o.infra.NetworkHostProject,
+			NetworkSelfLink:            o.infra.NetworkSelfLink,
+			ControlPlaneSubnetSelfLink: subnet,
+			WorkerSubnetSelfLink:       subnet,
+		}
+	case o.NetworkProject != "":
+		platform.Network = &hyperv1.GCPNetworkSpec{
+			HostProject:                o.NetworkProject,
+			NetworkSelfLink:            o.Network,
+			ControlPlaneSubnetSelfLink: o.Subnet,
+			WorkerSubnetSelfLink:       o.Subnet,
+		}
+	case o.infra != nil:
+		subnet := anySubnetSelfLink(o.infra.SubnetSelfLinks)
+		platform.Network = &hyperv1.GCPNetworkSpec{
+			HostProject:                o.Project,
+			NetworkSelfLink:            o.infra.NetworkSelfLink,
+			ControlPlaneSubnetSelfLink: subnet,
+			WorkerSubnetSelfLink:       subnet,
+		}
+	}
+	hostedCluster.Spec.Platform.GCP = platform
 	return nil
 }
 
-// GenerateNodePools generates the NodePool resources for GCP
+// anySubnetSelfLink returns an arbitrary subnet self-link from a zone ->
+// subnet map. GCP subnets are regional, so every zone in a dedicated VPC
+// maps to the same subnet; any entry is representative.
+func anySubnetSelfLink(subnetSelfLinks map[string]string) string {
+	for _, selfLink := range subnetSelfLinks {
+		return selfLink
+	}
+	return ""
+}
+
+// failureDomainZoneLabel matches the standard Kubernetes topology label used
+// to pin NodePools to a specific zone, consistent with how the AWS platform
+// labels its per-AZ NodePools.
+const failureDomainZoneLabel = "topology.kubernetes.io/zone"
+
+// GenerateNodePools generates the NodePool resources for GCP. One NodePool
+// is generated per requested zone, matching the AWS platform's per-AZ
+// NodePool behavior; with no zones requested, a single zoneless NodePool is
+// generated as before.
 func (o *CreateOptions) GenerateNodePools(constructor core.DefaultNodePoolConstructor) []*hyperv1.NodePool {
-	nodePool := constructor(hyperv1.GCPPlatform, "")
-	return []*hyperv1.NodePool{nodePool}
+	zones := o.Zones
+	if len(zones) == 0 {
+		zones = []string{""}
+	}
+
+	nodePools := make([]*hyperv1.NodePool, 0, len(zones))
+	for _, zone := range zones {
+		nodePool := constructor(hyperv1.GCPPlatform, "")
+		if zone != "" {
+			nodePool.Name = fmt.Sprintf("%s-%s", nodePool.Name, zone)
+			if nodePool.Labels == nil {
+				nodePool.Labels = map[string]string{}
+			}
+			nodePool.Labels[failureDomainZoneLabel] = zone
+		}
+
+		nodePool.Spec.Replicas = &o.NodeCount
+		nodePool.Spec.Platform.GCP = &hyperv1.GCPNodePoolPlatform{
+			Zone:                zone,
+			InstanceType:        o.InstanceType,
+			ServiceAccountEmail: o.serviceAccountEmail(),
+			ConfidentialCompute: o.ConfidentialCompute,
+			AutoRepair:          o.AutoRepair,
+			RootVolume: hyperv1.GCPVolume{
+				Size:          o.RootVolumeSize,
+				Type:          o.RootVolumeType,
+				EncryptionKey: o.RootVolumeEncryptionKey,
+			},
+		}
+
+		nodePools = append(nodePools, nodePool)
+	}
+
+	return nodePools
+}
+
+// serviceAccountEmail returns the email of the service account worker nodes
+// should run as: the user-supplied override if set, otherwise the
+// infra-created service account.
+func (o *CreateOptions) serviceAccountEmail() string {
+	if o.ServiceAccountEmail != "" {
+		return o.ServiceAccountEmail
+	}
+	if o.infra != nil {
+		return o.infra.ServiceAccountEmail
+	}
+	return ""
+}
+
+// credentialsSecretName is the name of the Secret GenerateResources emits
+// to carry the cluster's GCP service account identity to the control
+// plane components that need it.
+func credentialsSecretName(name string) string {
+	return fmt.Sprintf("%s-gcp-creds", name)
 }
 
 // GenerateResources generates additional resources for GCP
 func (o *CreateOptions) GenerateResources() ([]client.Object, error) {
-	return nil, nil
+	if o.infra == nil {
+		return nil, nil
+	}
+
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(o.name),
+			Namespace: o.namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			"service_account_email": o.infra.ServiceAccountEmail,
+			"project_id":            o.infra.Project,
+		},
+	}
+
+	return []client.Object{credentialsSecret}, nil
 }
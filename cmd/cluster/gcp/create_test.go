@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -10,10 +11,13 @@ import (
 
 	hyperv1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/hypershift/cmd/cluster/core"
+	gcpinfra "github.com/openshift/hypershift/cmd/infra/gcp"
 	"github.com/openshift/hypershift/support/certs"
 	"github.com/openshift/hypershift/support/testutil"
 	"github.com/openshift/hypershift/test/integration/framework"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 
 	"github.com/spf13/pflag"
@@ -155,6 +159,213 @@ func TestValidateRegion(t *testing.T) {
 	}
 }
 
+func TestValidateZone(t *testing.T) {
+	tests := map[string]struct {
+		region        string
+		zone          string
+		expectedError bool
+	}{
+		"valid zone":             {region: "us-central1", zone: "us-central1-a", expectedError: false},
+		"zone in different region": {region: "us-central1", zone: "us-west1-a", expectedError: true},
+		"zone missing suffix":    {region: "us-central1", zone: "us-central1", expectedError: true},
+		"zone with digit suffix": {region: "us-central1", zone: "us-central1-1", expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateZone(test.region, test.zone)
+			if test.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateInstanceType(t *testing.T) {
+	tests := map[string]struct {
+		instanceType  string
+		expectedError bool
+	}{
+		"valid standard type": {instanceType: "n2-standard-4", expectedError: false},
+		"valid short type":    {instanceType: "e2-medium", expectedError: false},
+		"empty":               {instanceType: "", expectedError: true},
+		"uppercase":           {instanceType: "N2-Standard-4", expectedError: true},
+		"no hyphen":           {instanceType: "nstandard4", expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateInstanceType(test.instanceType)
+			if test.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateRootVolumeSize(t *testing.T) {
+	tests := map[string]struct {
+		sizeGiB       int64
+		expectedError bool
+	}{
+		"unset uses default":  {sizeGiB: 0, expectedError: false},
+		"valid size":          {sizeGiB: 128, expectedError: false},
+		"too small":           {sizeGiB: 5, expectedError: true},
+		"too large":           {sizeGiB: 100000, expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateRootVolumeSize(test.sizeGiB)
+			if test.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateConfidentialCompute(t *testing.T) {
+	tests := map[string]struct {
+		confidentialCompute string
+		expectedError       bool
+	}{
+		"empty disables it":  {confidentialCompute: "", expectedError: false},
+		"SEV":                {confidentialCompute: "SEV", expectedError: false},
+		"SEV_SNP":            {confidentialCompute: "SEV_SNP", expectedError: false},
+		"TDX":                {confidentialCompute: "TDX", expectedError: false},
+		"invalid":            {confidentialCompute: "sev", expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateConfidentialCompute(test.confidentialCompute)
+			if test.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateSharedVPC(t *testing.T) {
+	tests := map[string]struct {
+		networkProject string
+		network        string
+		subnet         string
+		expectedError  bool
+	}{
+		"unset is valid (dedicated VPC)": {expectedError: false},
+		"all set is valid": {
+			networkProject: "host-project-123",
+			network:        "projects/host-project-123/global/networks/shared",
+			subnet:         "projects/host-project-123/regions/us-central1/subnetworks/shared",
+			expectedError:  false,
+		},
+		"network without network-project": {
+			network:       "projects/host-project-123/global/networks/shared",
+			subnet:        "projects/host-project-123/regions/us-central1/subnetworks/shared",
+			expectedError: true,
+		},
+		"network-project without subnet": {
+			networkProject: "host-project-123",
+			network:        "projects/host-project-123/global/networks/shared",
+			expectedError:  true,
+		},
+		"invalid network-project": {
+			networkProject: "bad_project",
+			network:        "projects/host-project-123/global/networks/shared",
+			subnet:         "projects/host-project-123/regions/us-central1/subnetworks/shared",
+			expectedError:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateSharedVPC(test.networkProject, test.network, test.subnet)
+			if test.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestApplyPlatformSpecificsSharedVPC(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{
+						Project:        "test-project-123",
+						Region:         "us-central1",
+						NetworkProject: "host-project-123",
+						Network:        "projects/host-project-123/global/networks/shared",
+						Subnet:         "projects/host-project-123/regions/us-central1/subnetworks/shared",
+					},
+				},
+			},
+		},
+	}
+
+	hostedCluster := &hyperv1.HostedCluster{}
+	err := opts.ApplyPlatformSpecifics(hostedCluster)
+	g.Expect(err).To(BeNil())
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network).ToNot(BeNil())
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.HostProject).To(Equal("host-project-123"))
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.NetworkSelfLink).To(Equal("projects/host-project-123/global/networks/shared"))
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.ControlPlaneSubnetSelfLink).To(Equal("projects/host-project-123/regions/us-central1/subnetworks/shared"))
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.WorkerSubnetSelfLink).To(Equal("projects/host-project-123/regions/us-central1/subnetworks/shared"))
+}
+
+// TestApplyPlatformSpecificsSharedVPCFromInfraJSON covers reusing
+// create-infra output for a Shared VPC cluster without repeating
+// --network-project at create-cluster time: the host project must come
+// from the resolved infra, not be misattributed to the service project.
+func TestApplyPlatformSpecificsSharedVPCFromInfraJSON(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{
+						Project: "service-project-123",
+						Region:  "us-central1",
+					},
+				},
+			},
+			infra: &gcpinfra.CreateInfraOutput{
+				NetworkHostProject: "host-project-123",
+				NetworkSelfLink:    "projects/host-project-123/global/networks/shared",
+				SubnetSelfLinks:    map[string]string{"us-central1-a": "projects/host-project-123/regions/us-central1/subnetworks/shared"},
+			},
+		},
+	}
+
+	hostedCluster := &hyperv1.HostedCluster{}
+	err := opts.ApplyPlatformSpecifics(hostedCluster)
+	g.Expect(err).To(BeNil())
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network).ToNot(BeNil())
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.HostProject).To(Equal("host-project-123"))
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.NetworkSelfLink).To(Equal("projects/host-project-123/global/networks/shared"))
+	g.Expect(hostedCluster.Spec.Platform.GCP.Network.ControlPlaneSubnetSelfLink).To(Equal("projects/host-project-123/regions/us-central1/subnetworks/shared"))
+}
+
 func TestRawCreateOptionsValidate(t *testing.T) {
 	tests := map[string]struct {
 		opts          *RawCreateOptions
@@ -162,36 +373,57 @@ func TestRawCreateOptionsValidate(t *testing.T) {
 	}{
 		"valid options": {
 			opts: &RawCreateOptions{
-				Project: "my-project-123",
-				Region:  "us-central1",
+				Project:      "my-project-123",
+				Region:       "us-central1",
+				InstanceType: "n2-standard-4",
 			},
 			expectedError: false,
 		},
 		"invalid project ID": {
 			opts: &RawCreateOptions{
-				Project: "bad_project",
-				Region:  "us-central1",
+				Project:      "bad_project",
+				Region:       "us-central1",
+				InstanceType: "n2-standard-4",
 			},
 			expectedError: true,
 		},
 		"invalid region": {
 			opts: &RawCreateOptions{
-				Project: "my-project-123",
-				Region:  "invalid-region",
+				Project:      "my-project-123",
+				Region:       "invalid-region",
+				InstanceType: "n2-standard-4",
 			},
 			expectedError: true,
 		},
 		"empty project ID": {
 			opts: &RawCreateOptions{
-				Project: "",
-				Region:  "us-central1",
+				Project:      "",
+				Region:       "us-central1",
+				InstanceType: "n2-standard-4",
 			},
 			expectedError: true,
 		},
 		"empty region": {
+			opts: &RawCreateOptions{
+				Project:      "my-project-123",
+				Region:       "",
+				InstanceType: "n2-standard-4",
+			},
+			expectedError: true,
+		},
+		"invalid zone for region": {
+			opts: &RawCreateOptions{
+				Project:      "my-project-123",
+				Region:       "us-central1",
+				Zones:        []string{"us-west1-a"},
+				InstanceType: "n2-standard-4",
+			},
+			expectedError: true,
+		},
+		"empty instance type": {
 			opts: &RawCreateOptions{
 				Project: "my-project-123",
-				Region:  "",
+				Region:  "us-central1",
 			},
 			expectedError: true,
 		},
@@ -215,7 +447,7 @@ func TestRawCreateOptionsValidate(t *testing.T) {
 
 func TestCreateOptionsApplyPlatformSpecifics(t *testing.T) {
 	g := NewGomegaWithT(t)
-	
+
 	opts := &CreateOptions{
 		completedCreateOptions: &completedCreateOptions{
 			ValidatedCreateOptions: &ValidatedCreateOptions{
@@ -226,44 +458,158 @@ func TestCreateOptionsApplyPlatformSpecifics(t *testing.T) {
 					},
 				},
 			},
+			infra: &gcpinfra.CreateInfraOutput{
+				ServiceAccountEmail: "cluster-sa@test-project-123.iam.gserviceaccount.com",
+			},
 		},
 	}
-	
+
 	hostedCluster := &hyperv1.HostedCluster{}
-	
+
 	err := opts.ApplyPlatformSpecifics(hostedCluster)
 	g.Expect(err).To(BeNil())
 	g.Expect(hostedCluster.Spec.Platform.Type).To(Equal(hyperv1.GCPPlatform))
 	g.Expect(hostedCluster.Spec.Platform.GCP).ToNot(BeNil())
 	g.Expect(hostedCluster.Spec.Platform.GCP.Project).To(Equal("test-project-123"))
 	g.Expect(hostedCluster.Spec.Platform.GCP.Region).To(Equal("us-central1"))
+	g.Expect(hostedCluster.Spec.Platform.GCP.ServiceAccountEmail).To(Equal("cluster-sa@test-project-123.iam.gserviceaccount.com"))
+}
+
+func TestCreateOptionsApplyPlatformSpecificsNoInfra(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{
+						Project: "test-project-123",
+						Region:  "us-central1",
+					},
+				},
+			},
+		},
+	}
+
+	hostedCluster := &hyperv1.HostedCluster{}
+
+	err := opts.ApplyPlatformSpecifics(hostedCluster)
+	g.Expect(err).To(BeNil())
+	g.Expect(hostedCluster.Spec.Platform.GCP.ServiceAccountEmail).To(Equal(""))
+}
+
+func nodePoolConstructor(platformType hyperv1.PlatformType, arch string) *hyperv1.NodePool {
+	return &hyperv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: hyperv1.NodePoolSpec{
+			Platform: hyperv1.NodePoolPlatform{
+				Type: platformType,
+			},
+		},
+	}
 }
 
 func TestGenerateNodePools(t *testing.T) {
 	g := NewGomegaWithT(t)
-	
-	opts := &CreateOptions{}
-	
-	constructor := func(platformType hyperv1.PlatformType, arch string) *hyperv1.NodePool {
-		return &hyperv1.NodePool{
-			Spec: hyperv1.NodePoolSpec{
-				Platform: hyperv1.NodePoolPlatform{
-					Type: platformType,
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{
+						Project:      "test-project-123",
+						Region:       "us-central1",
+						InstanceType: "n2-standard-4",
+						NodeCount:    2,
+					},
 				},
 			},
-		}
+		},
 	}
-	
-	nodePools := opts.GenerateNodePools(constructor)
+
+	nodePools := opts.GenerateNodePools(nodePoolConstructor)
 	g.Expect(nodePools).To(HaveLen(1))
 	g.Expect(nodePools[0].Spec.Platform.Type).To(Equal(hyperv1.GCPPlatform))
+	g.Expect(nodePools[0].Name).To(Equal("example"))
+	g.Expect(nodePools[0].Spec.Platform.GCP.Zone).To(Equal(""))
+	g.Expect(nodePools[0].Spec.Platform.GCP.InstanceType).To(Equal("n2-standard-4"))
+	g.Expect(*nodePools[0].Spec.Replicas).To(Equal(int32(2)))
+}
+
+func TestGenerateNodePoolsPerZone(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{
+						Project:      "test-project-123",
+						Region:       "us-central1",
+						Zones:        []string{"us-central1-a", "us-central1-b"},
+						InstanceType: "n2-standard-4",
+						NodeCount:    1,
+					},
+				},
+			},
+		},
+	}
+
+	nodePools := opts.GenerateNodePools(nodePoolConstructor)
+	g.Expect(nodePools).To(HaveLen(2))
+	g.Expect(nodePools[0].Name).To(Equal("example-us-central1-a"))
+	g.Expect(nodePools[0].Spec.Platform.GCP.Zone).To(Equal("us-central1-a"))
+	g.Expect(nodePools[0].Labels[failureDomainZoneLabel]).To(Equal("us-central1-a"))
+	g.Expect(nodePools[1].Name).To(Equal("example-us-central1-b"))
+	g.Expect(nodePools[1].Spec.Platform.GCP.Zone).To(Equal("us-central1-b"))
 }
 
 func TestGenerateResources(t *testing.T) {
 	g := NewGomegaWithT(t)
-	
-	opts := &CreateOptions{}
-	
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{
+						Project: "test-project-123",
+						Region:  "us-central1",
+					},
+				},
+			},
+			name:      "example",
+			namespace: "clusters",
+			infra: &gcpinfra.CreateInfraOutput{
+				Project:             "test-project-123",
+				ServiceAccountEmail: "cluster-sa@test-project-123.iam.gserviceaccount.com",
+			},
+		},
+	}
+
+	resources, err := opts.GenerateResources()
+	g.Expect(err).To(BeNil())
+	g.Expect(resources).To(HaveLen(1))
+
+	secret, ok := resources[0].(*corev1.Secret)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(secret.Name).To(Equal("example-gcp-creds"))
+	g.Expect(secret.Namespace).To(Equal("clusters"))
+	g.Expect(secret.StringData["service_account_email"]).To(Equal("cluster-sa@test-project-123.iam.gserviceaccount.com"))
+}
+
+func TestGenerateResourcesNoInfra(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	opts := &CreateOptions{
+		completedCreateOptions: &completedCreateOptions{
+			ValidatedCreateOptions: &ValidatedCreateOptions{
+				validatedCreateOptions: &validatedCreateOptions{
+					RawCreateOptions: &RawCreateOptions{},
+				},
+			},
+		},
+	}
+
 	resources, err := opts.GenerateResources()
 	g.Expect(err).To(BeNil())
 	g.Expect(resources).To(BeNil())
@@ -271,11 +617,16 @@ func TestGenerateResources(t *testing.T) {
 
 func TestDefaultOptions(t *testing.T) {
 	g := NewGomegaWithT(t)
-	
+
 	opts := DefaultOptions()
 	g.Expect(opts).ToNot(BeNil())
 	g.Expect(opts.Project).To(Equal(""))
 	g.Expect(opts.Region).To(Equal(""))
+	g.Expect(opts.InstanceType).To(Equal("n2-standard-4"))
+	g.Expect(opts.RootVolumeSize).To(Equal(int64(128)))
+	g.Expect(opts.RootVolumeType).To(Equal("pd-ssd"))
+	g.Expect(opts.NodeCount).To(Equal(int32(2)))
+	g.Expect(opts.AutoRepair).To(BeTrue())
 }
 
 func TestCreateCluster(t *testing.T) {
@@ -290,6 +641,22 @@ func TestCreateCluster(t *testing.T) {
 		t.Fatalf("failed to write pullSecret: %v", err)
 	}
 
+	infraJSONFile := filepath.Join(tempDir, "infra.json")
+	infra, err := json.Marshal(&gcpinfra.CreateInfraOutput{
+		InfraID:             "example-infra",
+		Project:             "test-project-123",
+		Region:              "us-central1",
+		NetworkSelfLink:     "projects/test-project-123/global/networks/example-infra-network",
+		SubnetSelfLinks:     map[string]string{"us-central1-a": "projects/test-project-123/regions/us-central1/subnetworks/example-infra-subnet"},
+		ServiceAccountEmail: "example-infra-sa@test-project-123.iam.gserviceaccount.com",
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal infra: %v", err)
+	}
+	if err := os.WriteFile(infraJSONFile, infra, 0600); err != nil {
+		t.Fatalf("failed to write infra json: %v", err)
+	}
+
 	for _, testCase := range []struct {
 		name string
 		args []string
@@ -302,6 +669,7 @@ func TestCreateCluster(t *testing.T) {
 				"--render-sensitive",
 				"--name=example",
 				"--pull-secret=" + pullSecretFile,
+				"--infra-json=" + infraJSONFile,
 			},
 		},
 	} {
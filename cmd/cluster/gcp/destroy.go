@@ -0,0 +1,57 @@
+package gcp
+
+import (
+	"github.com/openshift/hypershift/cmd/cluster/core"
+	gcpinfra "github.com/openshift/hypershift/cmd/infra/gcp"
+
+	"github.com/spf13/cobra"
+)
+
+// NewDestroyCommand creates a new cobra command for destroying GCP clusters.
+// It tears down the HostedCluster and then, unless --infra-json was used to
+// reuse externally managed infrastructure, the infra created alongside it.
+func NewDestroyCommand(opts *core.DestroyOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "gcp",
+		Short:        "Destroys a HostedCluster and its GCP infrastructure",
+		SilenceUsage: true,
+	}
+
+	gcpOpts := DefaultOptions()
+	BindOptions(gcpOpts, cmd.Flags())
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if err := core.DestroyCluster(ctx, opts); err != nil {
+			opts.Log.Error(err, "Failed to destroy cluster")
+			return err
+		}
+
+		if gcpOpts.InfraJSON != "" {
+			// Infrastructure was provisioned out of band; leave it for the
+			// caller to tear down with `hypershift destroy infra gcp`.
+			return nil
+		}
+
+		infraID := gcpOpts.InfraID
+		if infraID == "" {
+			infraID = opts.Name
+		}
+
+		destroyInfraOpts := &gcpinfra.DestroyInfraOptions{
+			Project:        gcpOpts.Project,
+			Region:         gcpOpts.Region,
+			Zones:          gcpOpts.Zones,
+			InfraID:        infraID,
+			NetworkProject: gcpOpts.NetworkProject,
+			Subnet:         gcpOpts.Subnet,
+		}
+		if err := destroyInfraOpts.Run(ctx, opts.Log); err != nil {
+			opts.Log.Error(err, "Failed to destroy infrastructure")
+			return err
+		}
+		return nil
+	}
+
+	return cmd
+}
@@ -0,0 +1,541 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+
+	"github.com/spf13/cobra"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	compute "google.golang.org/api/compute/v1"
+	iam "google.golang.org/api/iam/v1"
+)
+
+// CreateInfraOptions are the user-supplied options for provisioning the
+// prerequisite GCP infrastructure (network, NAT, firewall rules, and the
+// workload-identity service account) a HostedCluster needs before it can
+// be created.
+type CreateInfraOptions struct {
+	Project    string
+	Region     string
+	Zones      []string
+	InfraID    string
+	Name       string
+	IssuerURL  string
+	OutputFile string
+
+	// CredentialsFile is an optional path to a GCP service account key used
+	// to authenticate the calls this command makes. When empty, the
+	// ambient application-default credentials are used.
+	CredentialsFile string
+
+	// NetworkProject, Network, and Subnet describe an existing Shared VPC
+	// (host/service project) topology to attach the cluster to. When set,
+	// network, subnet, router, NAT, and firewall creation are skipped in
+	// favor of granting the cluster's service account the IAM bindings it
+	// needs on the shared subnet.
+	NetworkProject string
+	Network        string
+	Subnet         string
+}
+
+// sharedVPC reports whether a pre-existing Shared VPC network was supplied
+// instead of provisioning a dedicated one.
+func (o *CreateInfraOptions) sharedVPC() bool {
+	return o.NetworkProject != ""
+}
+
+// CreateInfraOutput is the set of resource identifiers produced by
+// CreateInfraOptions.Run. It is written to OutputFile (if set) and is also the shape
+// consumed by `hypershift create cluster gcp --infra-json`.
+type CreateInfraOutput struct {
+	InfraID string `json:"infraID"`
+	Project string `json:"project"`
+	Region  string `json:"region"`
+
+	NetworkSelfLink string            `json:"networkSelfLink"`
+	SubnetSelfLinks map[string]string `json:"subnetSelfLinks"` // zone -> subnet self-link
+	NATName         string            `json:"natName"`
+	RouterName      string            `json:"routerName"`
+
+	// NetworkHostProject is set to the Shared VPC host project ID when the
+	// network and subnets above live in a different project than Project
+	// (i.e. --network-project was supplied at create-infra time). It is
+	// empty for a dedicated VPC, where Project itself is the host project.
+	NetworkHostProject string `json:"networkHostProject,omitempty"`
+
+	ServiceAccountEmail string `json:"serviceAccountEmail"`
+}
+
+// NewCreateCommand returns the `hypershift create infra gcp` command.
+func NewCreateCommand() *cobra.Command {
+	opts := &CreateInfraOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "gcp",
+		Short:        "Creates GCP infrastructure resources for a HostedCluster",
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&opts.Project, "project", opts.Project, "GCP project ID to create resources in")
+	cmd.Flags().StringVar(&opts.Region, "region", opts.Region, "GCP region to create resources in")
+	cmd.Flags().StringArrayVar(&opts.Zones, "zone", opts.Zones, "GCP zone the cluster will use (may be specified multiple times). All zones share one regional subnet, which is only created when at least one zone is given")
+	cmd.Flags().StringVar(&opts.InfraID, "infra-id", opts.InfraID, "Cluster identifier used to prefix created resource names")
+	cmd.Flags().StringVar(&opts.Name, "name", opts.Name, "Name of the HostedCluster this infrastructure will back")
+	cmd.Flags().StringVar(&opts.IssuerURL, "issuer-url", opts.IssuerURL, "OIDC issuer URL the workload-identity service account is bound to")
+	cmd.Flags().StringVar(&opts.OutputFile, "output-file", opts.OutputFile, "Path to write the resulting infra JSON to")
+	cmd.Flags().StringVar(&opts.CredentialsFile, "credentials-file", opts.CredentialsFile, "Path to a GCP service account key to use for authentication")
+	cmd.Flags().StringVar(&opts.NetworkProject, "network-project", opts.NetworkProject, "GCP project ID of a Shared VPC host project to attach the cluster to, instead of creating a dedicated network")
+	cmd.Flags().StringVar(&opts.Network, "network", opts.Network, "Self-link of the Shared VPC network to attach the cluster to. Requires --network-project")
+	cmd.Flags().StringVar(&opts.Subnet, "subnet", opts.Subnet, "Self-link of the Shared VPC subnet to attach the cluster to. Requires --network-project")
+
+	_ = cmd.MarkFlagRequired("project")
+	_ = cmd.MarkFlagRequired("region")
+	_ = cmd.MarkFlagRequired("infra-id")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		log := logr.FromContextOrDiscard(ctx)
+
+		output, err := opts.Run(ctx, log)
+		if err != nil {
+			log.Error(err, "Failed to create infrastructure")
+			return err
+		}
+
+		if opts.OutputFile != "" {
+			rawOutput, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal infra output: %w", err)
+			}
+			if err := os.WriteFile(opts.OutputFile, rawOutput, 0644); err != nil {
+				return fmt.Errorf("failed to write infra output file: %w", err)
+			}
+		}
+
+		log.Info("Successfully created GCP infrastructure", "infraID", output.InfraID)
+		return nil
+	}
+
+	return cmd
+}
+
+// Run provisions the VPC, a regional subnet, Cloud NAT, firewall rules, and
+// workload-identity service account for a HostedCluster, and returns the
+// resulting resource identifiers. It is idempotent: resources that already
+// exist with the expected name are reused rather than recreated.
+func (o *CreateInfraOptions) Run(ctx context.Context, log logr.Logger) (*CreateInfraOutput, error) {
+	computeSvc, err := compute.NewService(ctx, credentialsOptions(o.CredentialsFile)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute client: %w", err)
+	}
+	iamSvc, err := iam.NewService(ctx, credentialsOptions(o.CredentialsFile)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iam client: %w", err)
+	}
+	crmSvc, err := cloudresourcemanager.NewService(ctx, credentialsOptions(o.CredentialsFile)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud resource manager client: %w", err)
+	}
+
+	output := &CreateInfraOutput{
+		InfraID:         o.InfraID,
+		Project:         o.Project,
+		Region:          o.Region,
+		SubnetSelfLinks: map[string]string{},
+	}
+
+	if o.sharedVPC() {
+		// The network lives in the host project and is managed out of
+		// band; just record the identifiers the caller supplied.
+		log.Info("Using Shared VPC network", "networkProject", o.NetworkProject, "network", o.Network)
+		output.NetworkHostProject = o.NetworkProject
+		output.NetworkSelfLink = o.Network
+		for _, zone := range o.Zones {
+			output.SubnetSelfLinks[zone] = o.Subnet
+		}
+	} else {
+		network, err := o.reconcileNetwork(ctx, log, computeSvc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile network: %w", err)
+		}
+		output.NetworkSelfLink = network.SelfLink
+
+		// GCP subnets are regional, not zonal: every zone within Region
+		// shares the same subnet, so only one is created regardless of how
+		// many zones were requested.
+		if len(o.Zones) > 0 {
+			subnet, err := o.reconcileSubnet(ctx, log, computeSvc, network)
+			if err != nil {
+				return nil, fmt.Errorf("failed to reconcile subnet: %w", err)
+			}
+			for _, zone := range o.Zones {
+				output.SubnetSelfLinks[zone] = subnet.SelfLink
+			}
+		}
+
+		router, err := o.reconcileRouter(ctx, log, computeSvc, network)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile Cloud Router: %w", err)
+		}
+		output.RouterName = router.Name
+
+		nat, err := o.reconcileNAT(ctx, log, computeSvc, router)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile Cloud NAT: %w", err)
+		}
+		output.NATName = nat.Name
+
+		if err := o.reconcileFirewallRules(ctx, log, computeSvc, network); err != nil {
+			return nil, fmt.Errorf("failed to reconcile firewall rules: %w", err)
+		}
+	}
+
+	serviceAccount, err := o.reconcileServiceAccount(ctx, log, iamSvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile service account: %w", err)
+	}
+	output.ServiceAccountEmail = serviceAccount.Email
+
+	if err := o.reconcileWorkloadIdentityBinding(ctx, log, iamSvc, serviceAccount); err != nil {
+		return nil, fmt.Errorf("failed to bind workload identity: %w", err)
+	}
+
+	if err := o.reconcileComponentRoleBindings(ctx, log, crmSvc, serviceAccount); err != nil {
+		return nil, fmt.Errorf("failed to reconcile component IAM role bindings: %w", err)
+	}
+
+	if o.sharedVPC() {
+		if err := o.reconcileSharedVPCBindings(ctx, log, computeSvc, serviceAccount); err != nil {
+			return nil, fmt.Errorf("failed to grant Shared VPC subnet access: %w", err)
+		}
+	}
+
+	return output, nil
+}
+
+func networkName(infraID string) string {
+	return fmt.Sprintf("%s-network", infraID)
+}
+
+func subnetName(infraID string) string {
+	return fmt.Sprintf("%s-subnet", infraID)
+}
+
+func routerName(infraID string) string {
+	return fmt.Sprintf("%s-router", infraID)
+}
+
+func natName(infraID string) string {
+	return fmt.Sprintf("%s-nat", infraID)
+}
+
+func serviceAccountID(infraID string) string {
+	// Service account IDs are limited to 30 characters, so the infra ID is
+	// truncated rather than the fixed "-sa" suffix.
+	id := infraID
+	if len(id) > 26 {
+		id = id[:26]
+	}
+	return fmt.Sprintf("%s-sa", id)
+}
+
+func (o *CreateInfraOptions) reconcileNetwork(ctx context.Context, log logr.Logger, svc *compute.Service) (*compute.Network, error) {
+	name := networkName(o.InfraID)
+	existing, err := svc.Networks.Get(o.Project, name).Context(ctx).Do()
+	if err == nil {
+		return existing, nil
+	}
+
+	log.Info("Creating VPC network", "name", name)
+	network := &compute.Network{
+		Name:                  name,
+		AutoCreateSubnetworks: false,
+	}
+	op, err := svc.Networks.Insert(o.Project, network).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForGlobalOperation(ctx, svc, o.Project, op); err != nil {
+		return nil, err
+	}
+	return svc.Networks.Get(o.Project, name).Context(ctx).Do()
+}
+
+func (o *CreateInfraOptions) reconcileSubnet(ctx context.Context, log logr.Logger, svc *compute.Service, network *compute.Network) (*compute.Subnetwork, error) {
+	name := subnetName(o.InfraID)
+	existing, err := svc.Subnetworks.Get(o.Project, o.Region, name).Context(ctx).Do()
+	if err == nil {
+		return existing, nil
+	}
+
+	log.Info("Creating subnet", "name", name, "region", o.Region)
+	subnet := &compute.Subnetwork{
+		Name:        name,
+		Network:     network.SelfLink,
+		Region:      o.Region,
+		IpCidrRange: "10.0.0.0/20",
+	}
+	op, err := svc.Subnetworks.Insert(o.Project, o.Region, subnet).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForRegionOperation(ctx, svc, o.Project, o.Region, op); err != nil {
+		return nil, err
+	}
+	return svc.Subnetworks.Get(o.Project, o.Region, name).Context(ctx).Do()
+}
+
+func (o *CreateInfraOptions) reconcileRouter(ctx context.Context, log logr.Logger, svc *compute.Service, network *compute.Network) (*compute.Router, error) {
+	name := routerName(o.InfraID)
+	existing, err := svc.Routers.Get(o.Project, o.Region, name).Context(ctx).Do()
+	if err == nil {
+		return existing, nil
+	}
+
+	log.Info("Creating Cloud Router", "name", name)
+	router := &compute.Router{
+		Name:    name,
+		Network: network.SelfLink,
+	}
+	op, err := svc.Routers.Insert(o.Project, o.Region, router).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForRegionOperation(ctx, svc, o.Project, o.Region, op); err != nil {
+		return nil, err
+	}
+	return svc.Routers.Get(o.Project, o.Region, name).Context(ctx).Do()
+}
+
+func (o *CreateInfraOptions) reconcileNAT(ctx context.Context, log logr.Logger, svc *compute.Service, router *compute.Router) (*compute.RouterNat, error) {
+	name := natName(o.InfraID)
+	for _, nat := range router.Nats {
+		if nat.Name == name {
+			return nat, nil
+		}
+	}
+
+	log.Info("Creating Cloud NAT", "name", name)
+	router.Nats = append(router.Nats, &compute.RouterNat{
+		Name:                          name,
+		NatIpAllocateOption:           "AUTO_ONLY",
+		SourceSubnetworkIpRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES",
+	})
+	op, err := svc.Routers.Patch(o.Project, o.Region, router.Name, router).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForRegionOperation(ctx, svc, o.Project, o.Region, op); err != nil {
+		return nil, err
+	}
+	updated, err := svc.Routers.Get(o.Project, o.Region, router.Name).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, nat := range updated.Nats {
+		if nat.Name == name {
+			return nat, nil
+		}
+	}
+	return nil, fmt.Errorf("NAT %s was not found on router %s after creation", name, router.Name)
+}
+
+func (o *CreateInfraOptions) reconcileFirewallRules(ctx context.Context, log logr.Logger, svc *compute.Service, network *compute.Network) error {
+	rules := []*compute.Firewall{
+		{
+			Name:         fmt.Sprintf("%s-allow-internal", o.InfraID),
+			Network:      network.SelfLink,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"10.0.0.0/8"},
+			Allowed: []*compute.FirewallAllowed{
+				{IPProtocol: "tcp"},
+				{IPProtocol: "udp"},
+				{IPProtocol: "icmp"},
+			},
+		},
+		{
+			Name:         fmt.Sprintf("%s-allow-health-checks", o.InfraID),
+			Network:      network.SelfLink,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"35.191.0.0/16", "130.211.0.0/22"},
+			Allowed: []*compute.FirewallAllowed{
+				{IPProtocol: "tcp"},
+			},
+		},
+	}
+
+	for _, rule := range rules {
+		if _, err := svc.Firewalls.Get(o.Project, rule.Name).Context(ctx).Do(); err == nil {
+			continue
+		}
+		log.Info("Creating firewall rule", "name", rule.Name)
+		op, err := svc.Firewalls.Insert(o.Project, rule).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if err := waitForGlobalOperation(ctx, svc, o.Project, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *CreateInfraOptions) reconcileServiceAccount(ctx context.Context, log logr.Logger, svc *iam.Service) (*iam.ServiceAccount, error) {
+	accountID := serviceAccountID(o.InfraID)
+	resource := accountResourceName(o.Project, accountID)
+
+	existing, err := svc.Projects.ServiceAccounts.Get(resource).Context(ctx).Do()
+	if err == nil {
+		return existing, nil
+	}
+
+	log.Info("Creating workload-identity service account", "accountID", accountID)
+	return svc.Projects.ServiceAccounts.Create(fmt.Sprintf("projects/%s", o.Project), &iam.CreateServiceAccountRequest{
+		AccountId: accountID,
+		ServiceAccount: &iam.ServiceAccount{
+			DisplayName: fmt.Sprintf("HyperShift cluster %s", o.Name),
+		},
+	}).Context(ctx).Do()
+}
+
+// reconcileWorkloadIdentityBinding grants the cluster's OIDC issuer
+// permission to impersonate the service account via Workload Identity
+// Federation, so in-cluster workloads can mint GCP credentials without a
+// long-lived service account key.
+func (o *CreateInfraOptions) reconcileWorkloadIdentityBinding(ctx context.Context, log logr.Logger, svc *iam.Service, serviceAccount *iam.ServiceAccount) error {
+	if o.IssuerURL == "" {
+		return nil
+	}
+
+	resource := serviceAccount.Name
+	policy, err := svc.Projects.ServiceAccounts.GetIamPolicy(resource).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	member := fmt.Sprintf("principal://iam.googleapis.com/%s", o.IssuerURL)
+	role := "roles/iam.workloadIdentityUser"
+	for _, binding := range policy.Bindings {
+		if binding.Role == role {
+			if containsString(binding.Members, member) {
+				return nil
+			}
+			binding.Members = append(binding.Members, member)
+			_, err := svc.Projects.ServiceAccounts.SetIamPolicy(resource, &iam.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+			return err
+		}
+	}
+
+	log.Info("Binding workload identity user role", "serviceAccount", serviceAccount.Email)
+	policy.Bindings = append(policy.Bindings, &iam.Binding{
+		Role:    role,
+		Members: []string{member},
+	})
+	_, err = svc.Projects.ServiceAccounts.SetIamPolicy(resource, &iam.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+	return err
+}
+
+// componentRoles lists the project-level IAM roles each HyperShift
+// control-plane component needs in order to reconcile its GCP resources.
+var componentRoles = []string{
+	"roles/compute.networkAdmin",      // cluster-network-config-controller
+	"roles/compute.loadBalancerAdmin", // cloud-provider-gcp
+	"roles/iam.serviceAccountUser",
+}
+
+// reconcileComponentRoleBindings grants the cluster's service account each
+// role in componentRoles on the project, so control-plane components running
+// under it can manage the networking and compute resources they own.
+func (o *CreateInfraOptions) reconcileComponentRoleBindings(ctx context.Context, log logr.Logger, svc *cloudresourcemanager.Service, serviceAccount *iam.ServiceAccount) error {
+	member := fmt.Sprintf("serviceAccount:%s", serviceAccount.Email)
+
+	policy, err := svc.Projects.GetIamPolicy(o.Project, &cloudresourcemanager.GetIamPolicyRequest{}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, role := range componentRoles {
+		var binding *cloudresourcemanager.Binding
+		for _, existing := range policy.Bindings {
+			if existing.Role == role {
+				binding = existing
+				break
+			}
+		}
+		if binding == nil {
+			binding = &cloudresourcemanager.Binding{Role: role}
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+		if !containsString(binding.Members, member) {
+			binding.Members = append(binding.Members, member)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	log.Info("Binding component IAM roles", "roles", componentRoles, "serviceAccount", serviceAccount.Email)
+	_, err = svc.Projects.SetIamPolicy(o.Project, &cloudresourcemanager.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do()
+	return err
+}
+
+// reconcileSharedVPCBindings grants the cluster's service account
+// roles/compute.networkUser on the Shared VPC subnet in the host project,
+// so it can attach instances and load balancers to a network it does not
+// own.
+func (o *CreateInfraOptions) reconcileSharedVPCBindings(ctx context.Context, log logr.Logger, svc *compute.Service, serviceAccount *iam.ServiceAccount) error {
+	const role = "roles/compute.networkUser"
+	member := fmt.Sprintf("serviceAccount:%s", serviceAccount.Email)
+
+	policy, err := svc.Subnetworks.GetIamPolicy(o.NetworkProject, o.Region, subnetNameFromSelfLink(o.Subnet)).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	for _, binding := range policy.Bindings {
+		if binding.Role == role {
+			if containsString(binding.Members, member) {
+				return nil
+			}
+			binding.Members = append(binding.Members, member)
+			_, err := svc.Subnetworks.SetIamPolicy(o.NetworkProject, o.Region, subnetNameFromSelfLink(o.Subnet), &compute.RegionSetPolicyRequest{Policy: policy}).Context(ctx).Do()
+			return err
+		}
+	}
+
+	log.Info("Granting Shared VPC subnet access", "subnet", o.Subnet, "serviceAccount", serviceAccount.Email)
+	policy.Bindings = append(policy.Bindings, &compute.Binding{
+		Role:    role,
+		Members: []string{member},
+	})
+	_, err = svc.Subnetworks.SetIamPolicy(o.NetworkProject, o.Region, subnetNameFromSelfLink(o.Subnet), &compute.RegionSetPolicyRequest{Policy: policy}).Context(ctx).Do()
+	return err
+}
+
+// subnetNameFromSelfLink extracts the trailing resource name from a
+// Compute Engine self-link (".../subnetworks/<name>").
+func subnetNameFromSelfLink(selfLink string) string {
+	for i := len(selfLink) - 1; i >= 0; i-- {
+		if selfLink[i] == '/' {
+			return selfLink[i+1:]
+		}
+	}
+	return selfLink
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
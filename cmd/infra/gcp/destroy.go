@@ -0,0 +1,214 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"github.com/spf13/cobra"
+
+	compute "google.golang.org/api/compute/v1"
+	iam "google.golang.org/api/iam/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// DestroyInfraOptions are the user-supplied options for tearing down the
+// infrastructure created by CreateInfraOptions.Run. Destroy is idempotent:
+// resources that are already absent are treated as success.
+type DestroyInfraOptions struct {
+	Project string
+	Region  string
+	Zones   []string
+	InfraID string
+
+	CredentialsFile string
+
+	// NetworkProject marks that a Shared VPC network was supplied at
+	// create time, so network, subnet, router, and NAT teardown is skipped
+	// here and left to the host project's owner.
+	NetworkProject string
+	// Subnet is the self-link of the Shared VPC subnet the cluster's
+	// service account was granted roles/compute.networkUser on. Required
+	// together with NetworkProject to revoke that binding.
+	Subnet string
+}
+
+func (o *DestroyInfraOptions) sharedVPC() bool {
+	return o.NetworkProject != ""
+}
+
+// NewDestroyCommand returns the `hypershift destroy infra gcp` command.
+func NewDestroyCommand() *cobra.Command {
+	opts := &DestroyInfraOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "gcp",
+		Short:        "Destroys GCP infrastructure resources for a HostedCluster",
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&opts.Project, "project", opts.Project, "GCP project ID the resources were created in")
+	cmd.Flags().StringVar(&opts.Region, "region", opts.Region, "GCP region the resources were created in")
+	cmd.Flags().StringArrayVar(&opts.Zones, "zone", opts.Zones, "GCP zone passed at create time (may be specified multiple times). The shared regional subnet is only deleted when at least one zone is given")
+	cmd.Flags().StringVar(&opts.InfraID, "infra-id", opts.InfraID, "Cluster identifier used to prefix created resource names")
+	cmd.Flags().StringVar(&opts.CredentialsFile, "credentials-file", opts.CredentialsFile, "Path to a GCP service account key to use for authentication")
+	cmd.Flags().StringVar(&opts.NetworkProject, "network-project", opts.NetworkProject, "GCP project ID of the Shared VPC host project supplied at create time. When set, network teardown is skipped")
+	cmd.Flags().StringVar(&opts.Subnet, "subnet", opts.Subnet, "Self-link of the Shared VPC subnet supplied at create time. Requires --network-project")
+
+	_ = cmd.MarkFlagRequired("project")
+	_ = cmd.MarkFlagRequired("region")
+	_ = cmd.MarkFlagRequired("infra-id")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		log := logr.FromContextOrDiscard(ctx)
+		return opts.Run(ctx, log)
+	}
+
+	return cmd
+}
+
+// Run tears down the service account, firewall rules, Cloud NAT, Cloud
+// Router, subnet, and VPC network for InfraID, in the reverse order they
+// were created. Each step is idempotent: a 404 from the API is treated as
+// already-deleted rather than an error.
+func (o *DestroyInfraOptions) Run(ctx context.Context, log logr.Logger) error {
+	computeSvc, err := compute.NewService(ctx, credentialsOptions(o.CredentialsFile)...)
+	if err != nil {
+		return err
+	}
+	iamSvc, err := iam.NewService(ctx, credentialsOptions(o.CredentialsFile)...)
+	if err != nil {
+		return err
+	}
+
+	accountID := serviceAccountID(o.InfraID)
+	resource := accountResourceName(o.Project, accountID)
+	email := serviceAccountEmailAddress(o.Project, accountID)
+
+	if o.sharedVPC() {
+		if err := o.reconcileSharedVPCUnbind(ctx, log, computeSvc, email); err != nil {
+			return fmt.Errorf("failed to revoke Shared VPC subnet access: %w", err)
+		}
+	}
+
+	log.Info("Deleting service account", "accountID", accountID)
+	if _, err := iamSvc.Projects.ServiceAccounts.Delete(resource).Context(ctx).Do(); err != nil && !isNotFound(err) {
+		return err
+	}
+
+	for _, name := range []string{
+		fmt.Sprintf("%s-allow-internal", o.InfraID),
+		fmt.Sprintf("%s-allow-health-checks", o.InfraID),
+	} {
+		log.Info("Deleting firewall rule", "name", name)
+		op, err := computeSvc.Firewalls.Delete(o.Project, name).Context(ctx).Do()
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if err := waitForGlobalOperation(ctx, computeSvc, o.Project, op); err != nil {
+			return err
+		}
+	}
+
+	if o.sharedVPC() {
+		// The network, subnets, router, and NAT live in the host project
+		// and are managed out of band; nothing more to tear down here.
+		return nil
+	}
+
+	router := routerName(o.InfraID)
+	log.Info("Deleting Cloud Router (and its NAT)", "name", router)
+	if op, err := computeSvc.Routers.Delete(o.Project, o.Region, router).Context(ctx).Do(); err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+	} else if err := waitForRegionOperation(ctx, computeSvc, o.Project, o.Region, op); err != nil {
+		return err
+	}
+
+	// GCP subnets are regional, so all zones in Region share the single
+	// subnet created by CreateInfraOptions.
+	if len(o.Zones) > 0 {
+		name := subnetName(o.InfraID)
+		log.Info("Deleting subnet", "name", name, "region", o.Region)
+		op, err := computeSvc.Subnetworks.Delete(o.Project, o.Region, name).Context(ctx).Do()
+		if err != nil {
+			if !isNotFound(err) {
+				return err
+			}
+		} else if err := waitForRegionOperation(ctx, computeSvc, o.Project, o.Region, op); err != nil {
+			return err
+		}
+	}
+
+	network := networkName(o.InfraID)
+	log.Info("Deleting VPC network", "name", network)
+	if op, err := computeSvc.Networks.Delete(o.Project, network).Context(ctx).Do(); err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+	} else if err := waitForGlobalOperation(ctx, computeSvc, o.Project, op); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reconcileSharedVPCUnbind revokes the roles/compute.networkUser binding
+// reconcileSharedVPCBindings grants on the Shared VPC subnet in the host
+// project, mirroring it in reverse so destroy doesn't leave a dangling IAM
+// binding for a service account it's about to delete.
+func (o *DestroyInfraOptions) reconcileSharedVPCUnbind(ctx context.Context, log logr.Logger, svc *compute.Service, serviceAccountEmail string) error {
+	const role = "roles/compute.networkUser"
+	member := fmt.Sprintf("serviceAccount:%s", serviceAccountEmail)
+	name := subnetNameFromSelfLink(o.Subnet)
+
+	policy, err := svc.Subnetworks.GetIamPolicy(o.NetworkProject, o.Region, name).Context(ctx).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	changed := false
+	for _, binding := range policy.Bindings {
+		if binding.Role != role {
+			continue
+		}
+		members := binding.Members[:0]
+		for _, m := range binding.Members {
+			if m == member {
+				changed = true
+				continue
+			}
+			members = append(members, m)
+		}
+		binding.Members = members
+	}
+	if !changed {
+		return nil
+	}
+
+	log.Info("Revoking Shared VPC subnet access", "subnet", o.Subnet, "serviceAccount", serviceAccountEmail)
+	_, err = svc.Subnetworks.SetIamPolicy(o.NetworkProject, o.Region, name, &compute.RegionSetPolicyRequest{Policy: policy}).Context(ctx).Do()
+	return err
+}
+
+func accountResourceName(project, accountID string) string {
+	return fmt.Sprintf("projects/%s/serviceAccounts/%s@%s.iam.gserviceaccount.com", project, accountID, project)
+}
+
+func serviceAccountEmailAddress(project, accountID string) string {
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", accountID, project)
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code == 404
+}
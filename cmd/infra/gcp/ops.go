@@ -0,0 +1,78 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+// credentialsOptions returns the client options needed to authenticate
+// with the given service account key file, or none to fall back to ambient
+// application-default credentials.
+func credentialsOptions(credentialsFile string) []option.ClientOption {
+	if credentialsFile == "" {
+		return nil
+	}
+	return []option.ClientOption{option.WithCredentialsFile(credentialsFile)}
+}
+
+const operationPollInterval = 2 * time.Second
+
+// waitForGlobalOperation blocks until a global compute operation (e.g.
+// network or firewall rule creation) completes, returning an error if the
+// operation itself failed.
+func waitForGlobalOperation(ctx context.Context, svc *compute.Service, project string, op *compute.Operation) error {
+	for {
+		current, err := svc.GlobalOperations.Get(project, op.Name).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if done, err := operationDone(current); done || err != nil {
+			return err
+		}
+		if err := sleep(ctx, operationPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForRegionOperation blocks until a regional compute operation (e.g.
+// subnet, router, or NAT creation) completes.
+func waitForRegionOperation(ctx context.Context, svc *compute.Service, project, region string, op *compute.Operation) error {
+	for {
+		current, err := svc.RegionOperations.Get(project, region, op.Name).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if done, err := operationDone(current); done || err != nil {
+			return err
+		}
+		if err := sleep(ctx, operationPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func operationDone(op *compute.Operation) (bool, error) {
+	if op.Status != "DONE" {
+		return false, nil
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return true, fmt.Errorf("operation %s failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+	return true, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}